@@ -0,0 +1,105 @@
+package zzacache
+
+import (
+	"sync"
+	"time"
+	"zzacache/lru"
+)
+
+// cache 对 lru.Cache 进行了一层封装，保证并发安全，并支持懒加载
+type cache struct {
+	mu         sync.Mutex
+	lru        *lru.Cache
+	cacheBytes int64
+	// newPolicy 决定了该 cache 底层使用的淘汰策略，为空时使用默认的 LRU
+	newPolicy  lru.PolicyConstructor
+	nhit, nget int64 // 命中与查询次数，供 Stats() 使用
+}
+
+// add 添加缓存，不设置过期时间
+func (c *cache) add(key string, value ByteView) {
+	c.addWithTTL(key, value, 0)
+}
+
+// addWithTTL 添加缓存并指定其存活时间，ttl <= 0 表示永不过期；
+// 首次调用时才会实例化 lru.Cache，以减小内存开销
+func (c *cache) addWithTTL(key string, value ByteView, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lru == nil {
+		newPolicy := c.newPolicy
+		if newPolicy == nil {
+			newPolicy = lru.NewLRUPolicy
+		}
+		c.lru = lru.NewWithPolicy(c.cacheBytes, newPolicy, nil)
+	}
+	c.lru.AddWithTTL(key, value, ttl)
+}
+
+// get 从 lru.Cache 中查找缓存
+func (c *cache) get(key string) (value ByteView, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nget++
+	if c.lru == nil {
+		return
+	}
+	if v, ok := c.lru.Get(key); ok {
+		c.nhit++
+		return v.(ByteView), ok
+	}
+	return
+}
+
+// getWithExpireAt 与 get 类似，但在同一次加锁内一并读出该 key 的过期时间，
+// 供调用方需要把值和过期时间作为一个整体对外暴露（例如 ServerHTTP 回传给对端）时使用，
+// 避免分两次独立加锁查询之间，该 key 被淘汰或被新的写入覆盖，导致值和过期时间不对应
+func (c *cache) getWithExpireAt(key string) (value ByteView, expireAt time.Time, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nget++
+	if c.lru == nil {
+		return
+	}
+	v, ok := c.lru.Get(key)
+	if !ok {
+		return ByteView{}, time.Time{}, false
+	}
+	c.nhit++
+	expireAt, _ = c.lru.ExpireAt(key)
+	return v.(ByteView), expireAt, true
+}
+
+// stats 返回该层缓存累计的命中次数与查询次数
+func (c *cache) stats() (nhit, nget int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nhit, c.nget
+}
+
+// expireAt 返回 key 当前的过期时间，零值表示永不过期；ok 为 false 表示
+// 该 key 不存在、已经过期，或底层 lru.Cache 尚未被实例化
+func (c *cache) expireAt(key string) (t time.Time, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lru == nil {
+		return time.Time{}, false
+	}
+	return c.lru.ExpireAt(key)
+}
+
+// startJanitor 启动一个后台 goroutine，按 interval 周期性扫描并清理过期的缓存项，
+// 在没有读写触发惰性淘汰的情况下也能及时释放内存、收紧最坏情况下的数据陈旧时间
+func (c *cache) startJanitor(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.mu.Lock()
+			if c.lru != nil {
+				c.lru.RemoveExpired()
+			}
+			c.mu.Unlock()
+		}
+	}()
+}
@@ -0,0 +1,29 @@
+package zzacache
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCacheStartJanitorRemovesExpired 验证 startJanitor 启动的后台 goroutine
+// 会在没有读写触发惰性淘汰的情况下，按 interval 周期性地清理已过期的条目
+func TestCacheStartJanitorRemovesExpired(t *testing.T) {
+	c := &cache{cacheBytes: 1 << 10}
+	c.addWithTTL("k1", ByteView{b: []byte("v1")}, time.Millisecond)
+	c.startJanitor(5 * time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.mu.Lock()
+		// 用 Len 而不是 Get 探测：Get 自身会惰性淘汰过期条目，无法证明是 janitor 清理的
+		n := c.lru.Len()
+		c.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("janitor did not remove expired key within 1s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
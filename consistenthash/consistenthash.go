@@ -30,11 +30,22 @@ func New(replicas int, fn Hash) *Map {
 	return m
 }
 
-// Add 函数允许传入 0 或 多个真实节点的名称
+// Add 函数允许传入 0 或 多个真实节点的名称，每个节点都按默认权重 1 创建 m.replicas 个虚拟节点
 func (m *Map) Add(keys ...string) {
-	// 对每一个真实节点 key，对应创建 m.replicas 个虚拟节点
+	m.AddWeighted(nil, keys...)
+}
+
+// AddWeighted 与 Add 类似，但允许通过 weights 为异构的真实节点（不同内存/CPU）指定不同的权重：
+// 节点 key 会获得 m.replicas * weights[key] 个虚拟节点，权重越高的节点在环上占据的比例越大，
+// 从而分摊更多的 key。weights 为 nil 或其中缺失某个 key 时，该 key 按权重 1 处理
+func (m *Map) AddWeighted(weights map[string]int, keys ...string) {
 	for _, key := range keys {
-		for i := 0; i < m.replicas; i++ {
+		weight := weights[key]
+		if weight <= 0 {
+			weight = 1
+		}
+		// 对每一个真实节点 key，对应创建 m.replicas * weight 个虚拟节点
+		for i := 0; i < m.replicas*weight; i++ {
 			// 虚拟节点的名称是：strconv.Itoa(i) + key
 			hash := int(m.hash([]byte(strconv.Itoa(i) + key)))
 			// 使用 m.hash() 计算虚拟节点的哈希值，使用 append(m.keys, hash) 添加到环上
@@ -46,6 +57,20 @@ func (m *Map) Add(keys ...string) {
 	sort.Ints(m.keys)
 }
 
+// Remove 将一个真实节点及其全部虚拟节点从环上摘除，使节点可以在集群运行期间动态下线
+func (m *Map) Remove(key string) {
+	kept := m.keys[:0]
+	for _, hash := range m.keys {
+		if m.hashMap[hash] == key {
+			delete(m.hashMap, hash)
+			continue
+		}
+		kept = append(kept, hash)
+	}
+	// kept 是 m.keys 底层数组上的原地过滤，相对顺序不变，因此无需重新排序
+	m.keys = kept
+}
+
 // Get 计算 key 的哈希值, 从 m.keys 中获取到对应的哈希值
 func (m *Map) Get(key string) string {
 	if len(m.keys) == 0 {
@@ -61,3 +86,30 @@ func (m *Map) Get(key string) string {
 	// 从 m.keys 中获取到对应的哈希值
 	return m.hashMap[m.keys[idx%len(m.keys)]]
 }
+
+// NodeStats 记录某个真实节点拥有的虚拟节点数，以及在抽样探测中落在该节点上的 key 数量
+type NodeStats struct {
+	VirtualNodes int
+	KeyCount     int
+}
+
+// Stats 对 samples 个探测 key 做 Get 查询，统计各个真实节点的虚拟节点数与命中次数，
+// 供运维人员校验节点间的负载是否均衡。samples 通常应远大于真实节点数，抽样才有意义
+func (m *Map) Stats(samples int) map[string]NodeStats {
+	stats := make(map[string]NodeStats)
+	for _, node := range m.hashMap {
+		s := stats[node]
+		s.VirtualNodes++
+		stats[node] = s
+	}
+	for i := 0; i < samples; i++ {
+		node := m.Get(strconv.Itoa(i) + "-probe")
+		if node == "" {
+			continue
+		}
+		s := stats[node]
+		s.KeyCount++
+		stats[node] = s
+	}
+	return stats
+}
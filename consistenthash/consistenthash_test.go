@@ -0,0 +1,90 @@
+package consistenthash
+
+import (
+	"strconv"
+	"testing"
+)
+
+// 使用一个可预测的哈希函数：把虚拟节点名称直接当作十进制数字解析，
+// 这样可以手算出每个 key 应该落在哪个虚拟节点上，断言更直观
+func identityHash(data []byte) uint32 {
+	n, _ := strconv.Atoi(string(data))
+	return uint32(n)
+}
+
+// TestGetPicksNearestClockwiseNode 验证 Get 返回顺时针方向最近的虚拟节点归属的真实节点
+func TestGetPicksNearestClockwiseNode(t *testing.T) {
+	m := New(3, identityHash)
+	// 虚拟节点 = strconv.Itoa(i)+key，replicas=3 时 i 取 0,1,2
+	// "06"、"16"、"26" 映射到真实节点 6
+	// 其余同理，构造出环: 2, 4, 6, 12, 14, 16, 22, 24, 26
+	m.Add("6", "4", "2")
+
+	cases := map[string]string{
+		"2":  "2",
+		"11": "2",
+		"23": "4",
+		"27": "2", // 环上最大的虚拟节点是 26，27 顺时针回绕到最小的虚拟节点 2
+	}
+	for key, want := range cases {
+		if got := m.Get(key); got != want {
+			t.Errorf("Get(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+// TestAddWeightedGivesHeavierNodeMoreVirtualNodes 验证权重越高的节点获得越多虚拟节点
+func TestAddWeightedGivesHeavierNodeMoreVirtualNodes(t *testing.T) {
+	m := New(10, nil)
+	m.AddWeighted(map[string]int{"heavy": 3, "light": 1}, "heavy", "light")
+
+	stats := m.Stats(1)
+	if stats["heavy"].VirtualNodes != 30 {
+		t.Fatalf("heavy VirtualNodes = %d, want 30", stats["heavy"].VirtualNodes)
+	}
+	if stats["light"].VirtualNodes != 10 {
+		t.Fatalf("light VirtualNodes = %d, want 10", stats["light"].VirtualNodes)
+	}
+}
+
+// TestAddWeightedNonPositiveFallsBackToDefault 验证权重 <= 0 时按默认权重 1 处理
+func TestAddWeightedNonPositiveFallsBackToDefault(t *testing.T) {
+	m := New(10, nil)
+	m.AddWeighted(map[string]int{"node": 0}, "node")
+
+	if got := m.Stats(1)["node"].VirtualNodes; got != 10 {
+		t.Fatalf("VirtualNodes = %d, want 10 (default weight 1)", got)
+	}
+}
+
+// TestRemoveTakesNodeOutOfRotation 验证 Remove 之后该节点不再被 Get 选中
+func TestRemoveTakesNodeOutOfRotation(t *testing.T) {
+	m := New(3, identityHash)
+	m.Add("6", "4", "2")
+	m.Remove("6")
+
+	for key := range map[string]struct{}{"2": {}, "11": {}, "23": {}, "27": {}} {
+		if got := m.Get(key); got == "6" {
+			t.Fatalf("Get(%q) = %q, want node 6 to have been removed", key, got)
+		}
+	}
+	if stats := m.Stats(1); stats["6"].VirtualNodes != 0 {
+		t.Fatalf("node 6 still has %d virtual nodes after Remove", stats["6"].VirtualNodes)
+	}
+}
+
+// TestStatsSamplesKeyDistribution 验证 Stats 对 samples 个探测 key 的命中次数求和等于 samples
+func TestStatsSamplesKeyDistribution(t *testing.T) {
+	m := New(50, nil)
+	m.Add("a", "b", "c")
+
+	const samples = 1000
+	stats := m.Stats(samples)
+	total := 0
+	for _, s := range stats {
+		total += s.KeyCount
+	}
+	if total != samples {
+		t.Fatalf("sum of KeyCount = %d, want %d", total, samples)
+	}
+}
@@ -0,0 +1,26 @@
+package zzacache
+
+import "context"
+
+// EventKind 描述一次 PeerEvent 代表的是节点加入还是离开
+type EventKind int
+
+const (
+	PeerAdd EventKind = iota
+	PeerRemove
+)
+
+// PeerEvent 描述一次节点成员变化
+type PeerEvent struct {
+	Kind   EventKind
+	Addr   string
+	Weight int // 仅在 Kind 为 PeerAdd 时有意义，<= 0 表示使用默认权重
+}
+
+// PeerDiscovery 屏蔽了节点成员关系的获取方式：实现者只需要不断地把成员变化推送到
+// Watch 返回的 channel 中，而不必像 HTTPPool.Set 那样要求调用方一次性给出完整的节点列表，
+// 从而让 zzacache 可以部署在节点会动态加入/退出的弹性集群中
+type PeerDiscovery interface {
+	// Watch 返回一个不断产出 PeerEvent 的 channel；ctx 被取消后应关闭该 channel
+	Watch(ctx context.Context) (<-chan PeerEvent, error)
+}
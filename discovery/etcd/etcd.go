@@ -0,0 +1,127 @@
+// Package etcd 提供基于 etcd 的 zzacache.PeerDiscovery 实现：每个节点在 etcd 中
+// 以 prefix+地址 为 key 租约一个条目并持续续约，其余节点通过 watch 该 prefix 来感知
+// 成员的加入、主动退出，以及租约过期导致的被动下线
+package etcd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"zzacache"
+)
+
+// Discovery 基于 etcd 的 prefix watch 实现节点发现
+type Discovery struct {
+	client *clientv3.Client
+	prefix string
+	ttl    int64 // 租约存活时间，单位秒
+
+	self       string // 本节点地址，非空时 Watch 会为自己注册并续约
+	selfWeight int
+}
+
+// New 创建一个基于 etcd 的 Discovery。self 非空时，Watch 会在 prefix 下为 self 租约一个
+// ttl 秒的条目并自动续约；self 为空时仅作为只读的观察者，不注册自己
+func New(client *clientv3.Client, prefix, self string, selfWeight int, ttl int64) *Discovery {
+	return &Discovery{client: client, prefix: prefix, ttl: ttl, self: self, selfWeight: selfWeight}
+}
+
+// Watch 实现 zzacache.PeerDiscovery：先列出 prefix 下现存的节点作为初始 Add 事件，
+// 再持续 watch 该 prefix，把后续的 PUT/DELETE 转换为 Add/Remove 事件
+func (d *Discovery) Watch(ctx context.Context) (<-chan zzacache.PeerEvent, error) {
+	if d.self != "" {
+		if err := d.register(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := d.client.Get(ctx, d.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan zzacache.PeerEvent)
+	go func() {
+		defer close(ch)
+		for _, kv := range resp.Kvs {
+			if !send(ctx, ch, zzacache.PeerEvent{
+				Kind:   zzacache.PeerAdd,
+				Addr:   strings.TrimPrefix(string(kv.Key), d.prefix),
+				Weight: parseWeight(string(kv.Value)),
+			}) {
+				return
+			}
+		}
+
+		watchCh := d.client.Watch(ctx, d.prefix, clientv3.WithPrefix(), clientv3.WithRev(resp.Header.Revision+1))
+		for wresp := range watchCh {
+			for _, ev := range wresp.Events {
+				addr := strings.TrimPrefix(string(ev.Kv.Key), d.prefix)
+				var pe zzacache.PeerEvent
+				switch ev.Type {
+				case clientv3.EventTypePut:
+					pe = zzacache.PeerEvent{Kind: zzacache.PeerAdd, Addr: addr, Weight: parseWeight(string(ev.Kv.Value))}
+				case clientv3.EventTypeDelete:
+					pe = zzacache.PeerEvent{Kind: zzacache.PeerRemove, Addr: addr}
+				default:
+					continue
+				}
+				if !send(ctx, ch, pe) {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// send 把 ev 投递到 ch，同时监听 ctx，避免 ctx 在消费方（HTTPPool.Watch）已经
+// 停止读取 ch 之后取消时，这里的发送永远阻塞导致 goroutine 泄漏。
+// 返回 false 表示 ctx 已取消，调用方应当放弃剩余事件并退出
+func send(ctx context.Context, ch chan<- zzacache.PeerEvent, ev zzacache.PeerEvent) bool {
+	select {
+	case ch <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// register 为本节点申请一个 ttl 秒的租约并写入 prefix+self -> weight，随后保持自动续约。
+// 节点异常退出（进程崩溃、网络分区）时，租约到期后会被 etcd 自动清理，
+// 其他节点据此感知到它已经下线
+func (d *Discovery) register(ctx context.Context) error {
+	lease, err := d.client.Grant(ctx, d.ttl)
+	if err != nil {
+		return err
+	}
+	key := d.prefix + d.self
+	value := strconv.Itoa(d.selfWeight)
+	if _, err := d.client.Put(ctx, key, value, clientv3.WithLease(lease.ID)); err != nil {
+		return err
+	}
+	keepAlive, err := d.client.KeepAlive(ctx, lease.ID)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for range keepAlive {
+			// 消费续约响应，保持租约存活；ctx 取消后 KeepAlive 的 channel 会被关闭
+		}
+	}()
+	return nil
+}
+
+func parseWeight(raw string) int {
+	w, err := strconv.Atoi(raw)
+	if err != nil {
+		return 1
+	}
+	return w
+}
+
+// 接口实现检查
+var _ zzacache.PeerDiscovery = (*Discovery)(nil)
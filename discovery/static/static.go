@@ -0,0 +1,33 @@
+// Package static 提供 zzacache.PeerDiscovery 最简单的实现：节点列表在创建时就固定下来，
+// 语义上与直接调用 HTTPPool.SetWeighted 等价，适用于不需要弹性伸缩的部署
+package static
+
+import (
+	"context"
+	"zzacache"
+)
+
+// Discovery 是一份固定的节点列表
+type Discovery struct {
+	peers   []string
+	weights map[string]int
+}
+
+// New 创建一个固定节点列表的 Discovery；weights 为 nil 时所有节点权重均为默认值 1
+func New(peers []string, weights map[string]int) *Discovery {
+	return &Discovery{peers: peers, weights: weights}
+}
+
+// Watch 实现 zzacache.PeerDiscovery：一次性把所有节点作为 Add 事件推入 channel 后关闭，
+// 不再产生后续事件
+func (d *Discovery) Watch(ctx context.Context) (<-chan zzacache.PeerEvent, error) {
+	ch := make(chan zzacache.PeerEvent, len(d.peers))
+	for _, peer := range d.peers {
+		ch <- zzacache.PeerEvent{Kind: zzacache.PeerAdd, Addr: peer, Weight: d.weights[peer]}
+	}
+	close(ch)
+	return ch, nil
+}
+
+// 接口实现检查
+var _ zzacache.PeerDiscovery = (*Discovery)(nil)
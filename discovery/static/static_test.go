@@ -0,0 +1,34 @@
+package static
+
+import (
+	"context"
+	"testing"
+
+	"zzacache"
+)
+
+// TestWatchEmitsAllPeersThenCloses 验证 Watch 把固定节点列表作为 Add 事件一次性推入 channel，
+// 按配置的 weights 携带权重，随后关闭 channel 不再产生后续事件
+func TestWatchEmitsAllPeersThenCloses(t *testing.T) {
+	d := New([]string{"a", "b"}, map[string]int{"a": 3})
+
+	events, err := d.Watch(context.Background())
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	got := make(map[string]zzacache.PeerEvent)
+	for ev := range events {
+		got[ev.Addr] = ev
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2", len(got))
+	}
+	if got["a"].Kind != zzacache.PeerAdd || got["a"].Weight != 3 {
+		t.Fatalf("event for a = %+v, want Kind=PeerAdd Weight=3", got["a"])
+	}
+	if got["b"].Kind != zzacache.PeerAdd || got["b"].Weight != 0 {
+		t.Fatalf("event for b = %+v, want Kind=PeerAdd Weight=0 (default)", got["b"])
+	}
+}
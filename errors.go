@@ -0,0 +1,9 @@
+package zzacache
+
+import "errors"
+
+// ErrKeyNotFound 应由 Getter/GetterWithTTL 实现在 key 确实不存在于数据源时返回，
+// 用以和数据源内部出错区分开。ServerHTTP 会把它翻译成 http.StatusNotFound，
+// 使远程调用方能够通过 httpGetter.Get 返回的 pb.ErrNotFound 识别出“key 缺失”
+// 而不是笼统地把所有错误都当作对端故障处理
+var ErrKeyNotFound = errors.New("zzacache: key not found")
@@ -1,6 +1,8 @@
 package zzacache
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -9,10 +11,13 @@ import (
 	"strings"
 	"sync"
 	"zzacache/consistenthash"
+	pb "zzacache/zzacachepb"
+
+	"github.com/golang/protobuf/proto"
 )
 
 const (
-	defaultBasePath = "/_zzacache"
+	defaultBasePath = "/_zzacache/"
 	defaultReplicas = 50
 )
 
@@ -25,13 +30,18 @@ type HTTPPool struct {
 	mu          sync.Mutex
 	peers       *consistenthash.Map    // peers，类型是一致性哈希算法的 Map，用来根据具体的 key 选择节点
 	httpGetters map[string]*httpGetter // 映射远程节点与对应的 httpGetter。每一个远程节点对应一个 httpGetter，因为 httpGetter 与远程节点的地址 baseURL 有关
+	// OnPeersChanged 在 Watch 处理完一次 PeerEvent 后被调用，告知调用方哪些节点被加入/摘除，
+	// 便于上层预热新节点的缓存，或者等待正在进行中的 RPC 结束后再真正下线节点
+	OnPeersChanged func(added, removed []string)
 }
 
 // NewHTTPPool initializes an HTTP pool of peers.
 func NewHTTPPool(self string) *HTTPPool {
 	return &HTTPPool{
-		self:     self,
-		basePath: defaultBasePath,
+		self:        self,
+		basePath:    defaultBasePath,
+		peers:       consistenthash.New(defaultReplicas, nil),
+		httpGetters: make(map[string]*httpGetter),
 	}
 }
 
@@ -63,17 +73,36 @@ func (p *HTTPPool) ServerHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 使用 group.Get(key) 获取缓存数据
+	// 使用 group.getWithExpireAt(key) 获取缓存数据；值和过期时间在同一次加锁内一并读出，
+	// 避免分两次独立查询之间该 key 被淘汰或覆盖写入新的 TTL
 	key := parts[1]
-	view, err := group.Get(key)
+	view, expireAt, fromHotCache, err := group.getWithExpireAt(key)
+	if err != nil {
+		// key 在数据源中确实不存在时返回 404，使对端的 httpGetter.Get 能将其
+		// 识别为 pb.ErrNotFound，而不是和数据源内部出错混为一谈
+		if errors.Is(err, ErrKeyNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 将缓存值序列化为 protobuf Response 消息，作为 httpResponse 的 body 返回
+	// 同时带上该 key 剩余的存活时间，使对端写入 hotCache 的副本能与源数据保持一致的过期时间。
+	// FromHotcache 告知对端这份数据本身已经是本节点的热点副本，对端据此避免再次复制
+	resp := &pb.Response{Value: view.ByteSlice(), FromHotcache: fromHotCache}
+	if !expireAt.IsZero() {
+		resp.ExpireAt = expireAt.Unix()
+	}
+	body, err := proto.Marshal(resp)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// 最终使用 w.Write() 将缓存值作为 httpResponse 的 body 返回
 	w.Header().Set("Content-Type", "application/octet-stream")
-	w.Write(view.ByteSlice())
+	w.Write(body)
 }
 
 // ===================================================================
@@ -83,49 +112,128 @@ type httpGetter struct {
 	baseURL string
 }
 
-func (h *httpGetter) Get(group string, key string) ([]byte, error) {
+func (h *httpGetter) Get(in *pb.Request, out *pb.Response) error {
 	// Sprintf 拼接字符串
 	// url.QueryEscape 检查字符串是否可以作为 URL 的一部分
 	// baseURL 表示将要访问的远程节点的地址，例如 http://example.com/_zzacache/
-	u := fmt.Sprintf("%v%v/%v", h.baseURL, url.QueryEscape(group), url.QueryEscape(key))
-
-	// 使用 http.Get() 方式获取返回值，并转换为 []bytes 类型
+	u := fmt.Sprintf(
+		"%v%v/%v",
+		h.baseURL,
+		url.QueryEscape(in.GetGroup()),
+		url.QueryEscape(in.GetKey()),
+	)
+
+	// 使用 http.Get() 方式获取返回值
 	res, err := http.Get(u)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotFound {
+		return pb.ErrNotFound
+	}
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned: %v", res.Status)
+		return fmt.Errorf("server returned: %v", res.Status)
 	}
 
 	bytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, fmt.Errorf("reading response body: %v", err)
+		return fmt.Errorf("reading response body: %v", err)
+	}
+
+	if err = proto.Unmarshal(bytes, out); err != nil {
+		return fmt.Errorf("decoding response body: %v", err)
 	}
 
-	return bytes, nil
+	return nil
 }
 
 // 接口实现检查
 // 在编译期验证 *httpGetter 类型是否完整实现了 PeerGetter 接口
 var _ PeerGetter = (*httpGetter)(nil)
 
-// Set 方法实例化了一致性哈希算法，并且添加了传入的节点, 并为每一个节点创建了一个 HTTP 客户端 httpGetter
+// Set 方法实例化了一致性哈希算法，并且添加了传入的节点（权重均为默认值 1）,
+// 并为每一个节点创建了一个 HTTP 客户端 httpGetter
 func (p *HTTPPool) Set(peers ...string) {
+	p.SetWeighted(nil, peers...)
+}
+
+// SetWeighted 与 Set 类似，但允许通过 weights 为异构的节点（不同内存/CPU）指定不同的权重，
+// 权重越高的节点会被分配越多的虚拟节点，从而承担更多比例的 key；weights 为 nil 时等价于 Set。
+// 二者都会重建整个一致性哈希环，适合启动时一次性确定节点列表；运行期间的增量变更请使用
+// AddPeer/RemovePeer
+func (p *HTTPPool) SetWeighted(weights map[string]int, peers ...string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	// 创建 consistenthashMap，每个真实结点对应 defaultReplicas 个虚拟结点
+	// 创建 consistenthashMap，每个真实结点对应 defaultReplicas * 权重 个虚拟结点
 	p.peers = consistenthash.New(defaultReplicas, nil)
 	// 添加真实结点，名称就是 peers 的每一个元素
-	p.peers.Add(peers...)
+	p.peers.AddWeighted(weights, peers...)
 	p.httpGetters = make(map[string]*httpGetter, len(peers))
 	for _, peer := range peers {
 		p.httpGetters[peer] = &httpGetter{baseURL: peer + p.basePath}
 	}
 }
 
+// AddPeer 向已有的一致性哈希环中增量添加一个节点，而不必重建整个环，
+// 使得节点可以在集群运行期间动态加入；weight <= 0 时按默认权重 1 处理
+func (p *HTTPPool) AddPeer(peer string, weight int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.peers.AddWeighted(map[string]int{peer: weight}, peer)
+	p.httpGetters[peer] = &httpGetter{baseURL: peer + p.basePath}
+}
+
+// RemovePeer 将一个节点及其对应的虚拟节点从环上摘除，
+// 使得节点可以在集群运行期间动态下线
+func (p *HTTPPool) RemovePeer(peer string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.peers.Remove(peer)
+	delete(p.httpGetters, peer)
+}
+
+// Watch 订阅 d 产出的节点成员变化事件，并将其转换为对一致性哈希环的增量更新
+// （AddPeer/RemovePeer），使 HTTPPool 不必在启动时就拿到完整的节点列表。
+// ctx 被取消时，后台 goroutine 随之退出
+func (p *HTTPPool) Watch(ctx context.Context, d PeerDiscovery) error {
+	events, err := d.Watch(ctx)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				p.applyPeerEvent(ev)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// applyPeerEvent 把单个 PeerEvent 应用到一致性哈希环，并在设置了 OnPeersChanged 时回调通知
+func (p *HTTPPool) applyPeerEvent(ev PeerEvent) {
+	switch ev.Kind {
+	case PeerAdd:
+		p.AddPeer(ev.Addr, ev.Weight)
+		if p.OnPeersChanged != nil {
+			p.OnPeersChanged([]string{ev.Addr}, nil)
+		}
+	case PeerRemove:
+		p.RemovePeer(ev.Addr)
+		if p.OnPeersChanged != nil {
+			p.OnPeersChanged(nil, []string{ev.Addr})
+		}
+	}
+}
+
 // PickPeer 包装了一致性哈希算法的 Get() 方法，根据具体的 key，选择节点，返回节点对应的 HTTP 客户端
 func (p *HTTPPool) PickPeer(key string) (PeerGetter, bool) {
 	p.mu.Lock()
@@ -0,0 +1,90 @@
+package zzacache
+
+import (
+	"context"
+	"testing"
+)
+
+// TestAddPeerRemovePeer 验证 AddPeer/RemovePeer 对一致性哈希环的增量更新立即对 PickPeer 可见
+func TestAddPeerRemovePeer(t *testing.T) {
+	p := NewHTTPPool("http://self")
+	p.AddPeer("http://peer1", 1)
+
+	if _, ok := p.PickPeer("anykey"); !ok {
+		t.Fatalf("PickPeer() ok = false after AddPeer, want true")
+	}
+
+	p.RemovePeer("http://peer1")
+	if _, ok := p.PickPeer("anykey"); ok {
+		t.Fatalf("PickPeer() ok = true after RemovePeer, want false (no peers left)")
+	}
+}
+
+// fakeDiscovery 是测试用的 PeerDiscovery，把预置的事件按顺序推入 channel 后关闭
+type fakeDiscovery struct {
+	events []PeerEvent
+}
+
+func (d *fakeDiscovery) Watch(ctx context.Context) (<-chan PeerEvent, error) {
+	ch := make(chan PeerEvent, len(d.events))
+	for _, ev := range d.events {
+		ch <- ev
+	}
+	close(ch)
+	return ch, nil
+}
+
+// TestWatchAppliesDiscoveryEvents 验证 Watch 把 PeerDiscovery 产出的 Add/Remove 事件
+// 应用到一致性哈希环，并通过 OnPeersChanged 通知调用方
+func TestWatchAppliesDiscoveryEvents(t *testing.T) {
+	p := NewHTTPPool("http://self")
+	d := &fakeDiscovery{events: []PeerEvent{
+		{Kind: PeerAdd, Addr: "http://peer1", Weight: 1},
+		{Kind: PeerAdd, Addr: "http://peer2", Weight: 1},
+		{Kind: PeerRemove, Addr: "http://peer1"},
+	}}
+
+	var added, removed []string
+	done := make(chan struct{})
+	var calls int
+	p.OnPeersChanged = func(a, r []string) {
+		added = append(added, a...)
+		removed = append(removed, r...)
+		calls++
+		if calls == len(d.events) {
+			close(done)
+		}
+	}
+
+	if err := p.Watch(context.Background(), d); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	<-done
+
+	if _, ok := p.httpGetters["http://peer1"]; ok {
+		t.Fatalf("peer1 should have been removed from httpGetters")
+	}
+	if _, ok := p.httpGetters["http://peer2"]; !ok {
+		t.Fatalf("peer2 should still be registered in httpGetters")
+	}
+	wantAdded := []string{"http://peer1", "http://peer2"}
+	wantRemoved := []string{"http://peer1"}
+	if !equalStrings(added, wantAdded) {
+		t.Fatalf("added = %v, want %v", added, wantAdded)
+	}
+	if !equalStrings(removed, wantRemoved) {
+		t.Fatalf("removed = %v, want %v", removed, wantRemoved)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package zzacache
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "zzacache/zzacachepb"
+)
+
+// TestServerHTTPKeyNotFound 验证 Getter 返回 ErrKeyNotFound 时，ServerHTTP 翻译为 404，
+// 使对端的 httpGetter.Get 能够识别出 pb.ErrNotFound，而不是笼统的 500
+func TestServerHTTPKeyNotFound(t *testing.T) {
+	NewGroup("httptest-missing", 2<<10, GetterFunc(func(key string) ([]byte, error) {
+		return nil, ErrKeyNotFound
+	}))
+	pool := NewHTTPPool("http://localhost:9999")
+
+	req := httptest.NewRequest("GET", pool.basePath+"httptest-missing/Tom", nil)
+	w := httptest.NewRecorder()
+	pool.ServerHTTP(w, req)
+
+	if w.Code != 404 {
+		t.Fatalf("ServerHTTP() status = %d, want 404", w.Code)
+	}
+}
+
+// TestServerHTTPHit 验证命中时返回 200，且 body 是可反序列化的 Response，值与源数据一致，
+// 且本节点拥有的数据（非 hotCache 副本）不会带上 FromHotcache
+func TestServerHTTPHit(t *testing.T) {
+	NewGroup("httptest-hit", 2<<10, GetterFunc(func(key string) ([]byte, error) {
+		return []byte(key + "!"), nil
+	}))
+	pool := NewHTTPPool("http://localhost:9999")
+
+	req := httptest.NewRequest("GET", pool.basePath+"httptest-hit/Tom", nil)
+	w := httptest.NewRecorder()
+	pool.ServerHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("ServerHTTP() status = %d, want 200", w.Code)
+	}
+	resp := &pb.Response{}
+	if err := proto.Unmarshal(w.Body.Bytes(), resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if string(resp.GetValue()) != "Tom!" {
+		t.Fatalf("resp.Value = %q, want %q", resp.GetValue(), "Tom!")
+	}
+	if resp.GetFromHotcache() {
+		t.Fatalf("resp.FromHotcache = true, want false for an entry owned by this node")
+	}
+}
+
+// TestServerHTTPHitFromHotCache 验证从 hotCache 命中时，响应带上 FromHotcache=true，
+// 使对端能够避免把这份已经是热点副本的数据再缓存一遍
+func TestServerHTTPHitFromHotCache(t *testing.T) {
+	g := NewGroup("httptest-hotcache-hit", 2<<10, GetterFunc(func(key string) ([]byte, error) {
+		t.Fatalf("Getter should not be called: value should come from hotCache")
+		return nil, nil
+	}))
+	g.hotCache.add("Tom", ByteView{b: []byte("Tom!")})
+	pool := NewHTTPPool("http://localhost:9999")
+
+	req := httptest.NewRequest("GET", pool.basePath+"httptest-hotcache-hit/Tom", nil)
+	w := httptest.NewRecorder()
+	pool.ServerHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("ServerHTTP() status = %d, want 200", w.Code)
+	}
+	resp := &pb.Response{}
+	if err := proto.Unmarshal(w.Body.Bytes(), resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !resp.GetFromHotcache() {
+		t.Fatalf("resp.FromHotcache = false, want true for an entry served from hotCache")
+	}
+}
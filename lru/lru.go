@@ -1,25 +1,31 @@
 package lru
 
-import "container/list"
+import "time"
 
+// Cache 是一个受字节预算限制的键值缓存。
+// 具体淘汰哪个 key 由可插拔的 Policy 决定，Cache 本身只负责存储、字节记账和过期判定
 type Cache struct {
 	// 允许使用的最大内存
 	maxBytes int64
 	// 当前已使用的内存
 	nbytes int64
-	// go 标准库的双向链表
-	ll *list.List
-	// 哈希表，键是字符串，值是双向链表结点
-	cache map[string]*list.Element
+	// 哈希表，键是字符串，值是缓存的数据
+	cache map[string]*entry
+	// policy 决定了超出 maxBytes 时应当淘汰哪一个 key
+	policy Policy
 	// 某条记录被移除时的回调函数
 	OnEvicated func(key string, value Value)
 }
 
-// entry 时双向链表结点的数据类型
+// entry 是哈希表中存储的数据
 type entry struct {
-	key string
-	// value 定义了一个结构体，能计算长度
 	value Value
+	// expireAt 为零值表示该结点永不过期
+	expireAt time.Time
+}
+
+func (e *entry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !e.expireAt.After(now)
 }
 
 // Value 用 Len 去计字节数，显示占用内存大小
@@ -27,68 +33,110 @@ type Value interface {
 	Len() int
 }
 
-// Cache 实例化方法
+// New 创建一个使用 LRU 淘汰策略的 Cache，与旧版本保持兼容
 func New(maxBytes int64, onEvicted func(string, Value)) *Cache {
+	return NewWithPolicy(maxBytes, NewLRUPolicy, onEvicted)
+}
+
+// NewWithPolicy 创建一个 Cache，使用 newPolicy 构造出的淘汰策略
+func NewWithPolicy(maxBytes int64, newPolicy PolicyConstructor, onEvicted func(string, Value)) *Cache {
 	return &Cache{
 		maxBytes:   maxBytes,
-		ll:         list.New(),
-		cache:      make(map[string]*list.Element),
+		cache:      make(map[string]*entry),
+		policy:     newPolicy(),
 		OnEvicated: onEvicted,
 	}
 }
 
-// 根据 key 查找 value
+// 根据 key 查找 value，已过期的结点会被当作未命中，并顺带触发淘汰
 func (c *Cache) Get(key string) (value Value, ok bool) {
-	// 如果 cache 中存在该 key
-	if ele, ok := c.cache[key]; ok {
-		// 将该结点移到双向链表最前面
-		c.ll.MoveToFront(ele)
-		// ele.Value 是双向链表结点， .(*entry) 表示把结点强制断言为 *entry 类型
-		kv := ele.Value.(*entry)
-		// 返回该结点的 value
-		return kv.value, true
+	e, ok := c.cache[key]
+	if !ok {
+		return nil, false
+	}
+	if e.expired(time.Now()) {
+		c.removeEntry(key, e)
+		return nil, false
 	}
-	return
+	// 告知 policy 该 key 被访问了一次
+	c.policy.OnAccess(key)
+	return e.value, true
 }
 
-// 删除最近最少访问结点，即双向链表末尾结点
+// ExpireAt 返回 key 当前的过期时间，零值表示永不过期；
+// ok 为 false 表示该 key 不存在或已经过期
+func (c *Cache) ExpireAt(key string) (expireAt time.Time, ok bool) {
+	e, exists := c.cache[key]
+	if !exists || e.expired(time.Now()) {
+		return time.Time{}, false
+	}
+	return e.expireAt, true
+}
+
+// 淘汰一个 key，具体淘汰哪一个由 policy 决定
 func (c *Cache) RemoveOldest() {
-	// 获取双向链表末尾结点
-	ele := c.ll.Back()
-	if ele != nil {
-		c.ll.Remove(ele)
-		kv := ele.Value.(*entry)
-		// 将改键从哈希表中删除
-		delete(c.cache, kv.key)
-		// 当前已使用内存要减去删去的结点占用的内存
-		c.nbytes -= int64(len(kv.key)) + int64(kv.value.Len())
-		// 如果有回调函数，则调用回调函数
-		if c.OnEvicated != nil {
-			c.OnEvicated(kv.key, kv.value)
+	key, ok := c.policy.Evict()
+	if !ok {
+		return
+	}
+	e := c.cache[key]
+	delete(c.cache, key)
+	// 当前已使用内存要减去删去的结点占用的内存
+	c.nbytes -= int64(len(key)) + int64(e.value.Len())
+	// 如果有回调函数，则调用回调函数
+	if c.OnEvicated != nil {
+		c.OnEvicated(key, e.value)
+	}
+}
+
+// removeEntry 主动移除一个已知存在的结点（例如被发现已过期），
+// 与 RemoveOldest 的区别是淘汰对象不是由 policy 选出的，因此需要先告知 policy 同步清理
+func (c *Cache) removeEntry(key string, e *entry) {
+	delete(c.cache, key)
+	c.policy.OnRemove(key)
+	c.nbytes -= int64(len(key)) + int64(e.value.Len())
+	if c.OnEvicated != nil {
+		c.OnEvicated(key, e.value)
+	}
+}
+
+// RemoveExpired 扫描所有结点并淘汰其中已经过期的部分，用于配合后台 janitor 协程，
+// 在没有读写触发惰性淘汰的情况下也能及时释放内存。调用方需自行保证并发安全
+func (c *Cache) RemoveExpired() {
+	now := time.Now()
+	for key, e := range c.cache {
+		if e.expired(now) {
+			c.removeEntry(key, e)
 		}
 	}
 }
 
-// 新增 or 修改结点
+// Add 新增 or 修改结点，不设置过期时间
 func (c *Cache) Add(key string, value Value) {
+	c.AddWithTTL(key, value, 0)
+}
+
+// AddWithTTL 新增 or 修改结点，并指定其存活时间；ttl <= 0 表示永不过期
+func (c *Cache) AddWithTTL(key string, value Value, ttl time.Duration) {
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
 	// 如果当前哈希表中已经有该 key 了
-	if ele, ok := c.cache[key]; ok {
-		// 将该节点移动到双向链表最前端
-		c.ll.MoveToFront(ele)
-		kv := ele.Value.(*entry)
+	if e, ok := c.cache[key]; ok {
 		// 将当前占用内存，加上新增 value 的内存大小，减去原本 value 的内存大小
-		c.nbytes += int64(value.Len()) - int64(kv.value.Len())
-		// 将该节点的 value 修改为新添加的 value
-		kv.value = value
+		c.nbytes += int64(value.Len()) - int64(e.value.Len())
+		e.value = value
+		e.expireAt = expireAt
+		c.policy.OnAccess(key)
 	} else {
-		// 如果当前哈希表中没有该 key，则将新增结点推入双向链表最前面
-		ele := c.ll.PushFront(&entry{key, value})
-		// 再加入到哈希表中
-		c.cache[key] = ele
+		// 如果当前哈希表中没有该 key，则新增一条记录
+		c.cache[key] = &entry{value: value, expireAt: expireAt}
 		// 再在当前占用内存加上新增结点的内存大小
 		c.nbytes += int64(len(key)) + int64(value.Len())
+		c.policy.OnAdd(key, value.Len())
 	}
-	// 如果当前占用内存找过最大占用内存，则一直删除双向链表尾部结点，直到当前占用内存小于最大占用内存
+	// 如果当前占用内存超过最大占用内存，则一直淘汰，直到当前占用内存小于最大占用内存
 	for c.maxBytes != 0 && c.maxBytes < c.nbytes {
 		c.RemoveOldest()
 	}
@@ -96,5 +144,5 @@ func (c *Cache) Add(key string, value Value) {
 
 // 获取当前结点数量
 func (c *Cache) Len() int {
-	return c.ll.Len()
+	return len(c.cache)
 }
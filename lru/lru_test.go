@@ -0,0 +1,69 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAddWithTTLExpires 验证带 TTL 的 key 过期后 Get 将其视为未命中，并触发淘汰回调
+func TestAddWithTTLExpires(t *testing.T) {
+	var evicted string
+	c := New(1<<10, func(key string, value Value) { evicted = key })
+	c.AddWithTTL("k1", stringValue("v1"), time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("k1"); ok {
+		t.Fatalf("expired key should not be returned by Get")
+	}
+	if evicted != "k1" {
+		t.Fatalf("OnEvicated was not called for expired key, got %q", evicted)
+	}
+}
+
+// TestAddWithoutTTLNeverExpires 验证 ttl <= 0 时 key 永不过期
+func TestAddWithoutTTLNeverExpires(t *testing.T) {
+	c := New(1<<10, nil)
+	c.Add("k1", stringValue("v1"))
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("k1"); !ok {
+		t.Fatalf("key without TTL should never expire")
+	}
+}
+
+// TestExpireAt 验证 ExpireAt 返回的过期时间与写入时一致，key 不存在或已过期时 ok 为 false
+func TestExpireAt(t *testing.T) {
+	c := New(1<<10, nil)
+	c.AddWithTTL("k1", stringValue("v1"), time.Hour)
+
+	expireAt, ok := c.ExpireAt("k1")
+	if !ok {
+		t.Fatalf("ExpireAt(k1) ok = false, want true")
+	}
+	if time.Until(expireAt) <= 0 || time.Until(expireAt) > time.Hour {
+		t.Fatalf("ExpireAt(k1) = %v, want roughly now+1h", expireAt)
+	}
+
+	if _, ok := c.ExpireAt("missing"); ok {
+		t.Fatalf("ExpireAt(missing) ok = true, want false")
+	}
+}
+
+// TestRemoveExpired 验证 RemoveExpired 会清理已过期的 key，保留未过期的 key
+func TestRemoveExpired(t *testing.T) {
+	c := New(1<<10, nil)
+	c.AddWithTTL("expired", stringValue("v1"), time.Millisecond)
+	c.AddWithTTL("alive", stringValue("v2"), time.Hour)
+
+	time.Sleep(5 * time.Millisecond)
+	c.RemoveExpired()
+
+	if c.Len() != 1 {
+		t.Fatalf("Len() = %d after RemoveExpired, want 1", c.Len())
+	}
+	if _, ok := c.Get("alive"); !ok {
+		t.Fatalf("alive key should survive RemoveExpired")
+	}
+}
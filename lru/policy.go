@@ -0,0 +1,19 @@
+package lru
+
+// Policy 决定了 Cache 在字节预算超限时应当淘汰哪一个 key。
+// Cache 本身只维护 key 到 value 的映射和已用字节数，具体的淘汰顺序完全交给 Policy 维护，
+// 这样不同的工作负载就可以选择不同的淘汰策略（LRU/LFU/FIFO...），而不必改动 Cache 本身
+type Policy interface {
+	// OnAccess 在某个已存在的 key 被访问（Get 命中，或 Add 更新已存在的 key）时调用
+	OnAccess(key string)
+	// OnAdd 在某个 key 首次被插入时调用，size 为该 key 新增占用的字节数
+	OnAdd(key string, size int)
+	// OnRemove 在某个 key 被主动删除时调用，使 Policy 同步清理自身的内部状态
+	OnRemove(key string)
+	// Evict 返回当前最应当被淘汰的 key，并将其从 Policy 内部状态中移除；
+	// 没有可淘汰的 key 时 ok 为 false
+	Evict() (key string, ok bool)
+}
+
+// PolicyConstructor 创建一个全新的 Policy 实例，配合 NewWithPolicy 使用
+type PolicyConstructor func() Policy
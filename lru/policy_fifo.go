@@ -0,0 +1,44 @@
+package lru
+
+import "container/list"
+
+// fifoPolicy 是先进先出淘汰策略：访问不影响淘汰顺序，只按插入顺序淘汰最早加入的 key
+type fifoPolicy struct {
+	ll    *list.List
+	elems map[string]*list.Element
+}
+
+// NewFIFOPolicy 创建一个 FIFO Policy
+func NewFIFOPolicy() Policy {
+	return &fifoPolicy{
+		ll:    list.New(),
+		elems: make(map[string]*list.Element),
+	}
+}
+
+func (p *fifoPolicy) OnAccess(key string) {}
+
+func (p *fifoPolicy) OnAdd(key string, size int) {
+	if _, ok := p.elems[key]; ok {
+		return
+	}
+	p.elems[key] = p.ll.PushBack(key)
+}
+
+func (p *fifoPolicy) OnRemove(key string) {
+	if ele, ok := p.elems[key]; ok {
+		p.ll.Remove(ele)
+		delete(p.elems, key)
+	}
+}
+
+func (p *fifoPolicy) Evict() (key string, ok bool) {
+	ele := p.ll.Front()
+	if ele == nil {
+		return "", false
+	}
+	key = ele.Value.(string)
+	p.ll.Remove(ele)
+	delete(p.elems, key)
+	return key, true
+}
@@ -0,0 +1,94 @@
+package lru
+
+import "container/heap"
+
+// lfuEntry 是 lfuHeap 中的一个节点
+type lfuEntry struct {
+	key   string
+	freq  int
+	seq   int // 插入顺序，freq 相同时更早插入的先被淘汰
+	index int
+}
+
+// lfuHeap 是按 (freq, seq) 升序排列的最小堆，堆顶即最应被淘汰的 key
+type lfuHeap []*lfuEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *lfuHeap) Push(x interface{}) {
+	e := x.(*lfuEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *lfuHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
+
+// lfuPolicy 是最不经常使用淘汰策略：淘汰访问次数最少的 key，
+// 访问次数相同时淘汰最早插入的 key
+type lfuPolicy struct {
+	h     lfuHeap
+	elems map[string]*lfuEntry
+	seq   int
+}
+
+// NewLFUPolicy 创建一个 LFU Policy
+func NewLFUPolicy() Policy {
+	return &lfuPolicy{
+		elems: make(map[string]*lfuEntry),
+	}
+}
+
+func (p *lfuPolicy) OnAccess(key string) {
+	if e, ok := p.elems[key]; ok {
+		e.freq++
+		heap.Fix(&p.h, e.index)
+	}
+}
+
+func (p *lfuPolicy) OnAdd(key string, size int) {
+	if e, ok := p.elems[key]; ok {
+		e.freq++
+		heap.Fix(&p.h, e.index)
+		return
+	}
+	e := &lfuEntry{key: key, freq: 1, seq: p.seq}
+	p.seq++
+	p.elems[key] = e
+	heap.Push(&p.h, e)
+}
+
+func (p *lfuPolicy) OnRemove(key string) {
+	if e, ok := p.elems[key]; ok {
+		heap.Remove(&p.h, e.index)
+		delete(p.elems, key)
+	}
+}
+
+func (p *lfuPolicy) Evict() (key string, ok bool) {
+	if p.h.Len() == 0 {
+		return "", false
+	}
+	e := heap.Pop(&p.h).(*lfuEntry)
+	delete(p.elems, e.key)
+	return e.key, true
+}
@@ -0,0 +1,49 @@
+package lru
+
+import "container/list"
+
+// lruPolicy 是最近最少使用淘汰策略：每次访问都把 key 移到队首，淘汰时取队尾
+type lruPolicy struct {
+	ll    *list.List
+	elems map[string]*list.Element
+}
+
+// NewLRUPolicy 创建一个 LRU Policy
+func NewLRUPolicy() Policy {
+	return &lruPolicy{
+		ll:    list.New(),
+		elems: make(map[string]*list.Element),
+	}
+}
+
+func (p *lruPolicy) OnAccess(key string) {
+	if ele, ok := p.elems[key]; ok {
+		p.ll.MoveToFront(ele)
+	}
+}
+
+func (p *lruPolicy) OnAdd(key string, size int) {
+	if ele, ok := p.elems[key]; ok {
+		p.ll.MoveToFront(ele)
+		return
+	}
+	p.elems[key] = p.ll.PushFront(key)
+}
+
+func (p *lruPolicy) OnRemove(key string) {
+	if ele, ok := p.elems[key]; ok {
+		p.ll.Remove(ele)
+		delete(p.elems, key)
+	}
+}
+
+func (p *lruPolicy) Evict() (key string, ok bool) {
+	ele := p.ll.Back()
+	if ele == nil {
+		return "", false
+	}
+	key = ele.Value.(string)
+	p.ll.Remove(ele)
+	delete(p.elems, key)
+	return key, true
+}
@@ -0,0 +1,63 @@
+package lru
+
+import "testing"
+
+type stringValue string
+
+func (s stringValue) Len() int { return len(s) }
+
+// TestFIFOPolicyEvictsInsertionOrder 验证 FIFO 策略完全按插入顺序淘汰，访问不影响顺序
+func TestFIFOPolicyEvictsInsertionOrder(t *testing.T) {
+	c := NewWithPolicy(10, NewFIFOPolicy, nil)
+	c.Add("k1", stringValue("v1")) // 4 字节 (key+value)
+	c.Add("k2", stringValue("v2")) // 4 字节，累计 8
+	// 访问 k1 不应该影响 FIFO 的淘汰顺序
+	c.Get("k1")
+	c.Add("k3", stringValue("v3")) // 4 字节，超出预算，应当淘汰最早插入的 k1
+
+	if _, ok := c.Get("k1"); ok {
+		t.Fatalf("k1 should have been evicted despite being accessed")
+	}
+	if _, ok := c.Get("k2"); !ok {
+		t.Fatalf("k2 should still be present")
+	}
+	if _, ok := c.Get("k3"); !ok {
+		t.Fatalf("k3 should still be present")
+	}
+}
+
+// TestLFUPolicyEvictsLeastFrequentlyUsed 验证 LFU 策略淘汰访问次数最少的 key
+func TestLFUPolicyEvictsLeastFrequentlyUsed(t *testing.T) {
+	c := NewWithPolicy(10, NewLFUPolicy, nil)
+	c.Add("k1", stringValue("v1"))
+	c.Add("k2", stringValue("v2"))
+	// k1 被额外访问两次，访问频率高于 k2，不应被优先淘汰
+	c.Get("k1")
+	c.Get("k1")
+	c.Add("k3", stringValue("v3")) // 超出预算，应当淘汰访问次数最少的 k2
+
+	if _, ok := c.Get("k2"); ok {
+		t.Fatalf("k2 should have been evicted as the least frequently used entry")
+	}
+	if _, ok := c.Get("k1"); !ok {
+		t.Fatalf("k1 should still be present")
+	}
+	if _, ok := c.Get("k3"); !ok {
+		t.Fatalf("k3 should still be present")
+	}
+}
+
+// TestLFUPolicyTieBreaksByInsertionOrder 验证访问次数相同时，LFU 淘汰更早插入的 key
+func TestLFUPolicyTieBreaksByInsertionOrder(t *testing.T) {
+	c := NewWithPolicy(10, NewLFUPolicy, nil)
+	c.Add("k1", stringValue("v1")) // 先插入，频率都是 1
+	c.Add("k2", stringValue("v2"))
+	c.Add("k3", stringValue("v3")) // 超出预算，频率相同时应淘汰最早插入的 k1
+
+	if _, ok := c.Get("k1"); ok {
+		t.Fatalf("k1 should have been evicted as the earliest inserted entry among equal frequencies")
+	}
+	if _, ok := c.Get("k2"); !ok {
+		t.Fatalf("k2 should still be present")
+	}
+}
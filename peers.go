@@ -0,0 +1,13 @@
+package zzacache
+
+import pb "zzacache/zzacachepb"
+
+// PeerPicker 定义了根据传入的 key 选择相应节点 PeerGetter 的方法
+type PeerPicker interface {
+	PickPeer(key string) (peer PeerGetter, ok bool)
+}
+
+// PeerGetter 定义了从对应 group 中查找缓存值的方法，对应上述流程中的 HTTP 客户端
+type PeerGetter interface {
+	Get(in *pb.Request, out *pb.Response) error
+}
@@ -0,0 +1,84 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestGroupDoCollapsesConcurrentCalls 验证同一个 key 并发调用 Do 时，
+// fn 只会被执行一次，所有调用方共享同一个结果。fn 阻塞在一个 channel 上，
+// 测试先用短暂的 sleep 等所有 goroutine 都已经进入 Do 并排队等待，再统一放行，
+// 确保它们真正并发重叠，而不是一个接一个地串行跑完
+func TestGroupDoCollapsesConcurrentCalls(t *testing.T) {
+	var g Group
+	var calls int32
+	var wg sync.WaitGroup
+	release := make(chan string)
+
+	const n = 100
+	results := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := g.Do("key", func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				return <-release, nil
+			})
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+			}
+			results[i] = v
+		}(i)
+	}
+	time.Sleep(100 * time.Millisecond) // 让所有 goroutine 都进入 Do
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("fn called %d times, want 1", calls)
+	}
+	for i, v := range results {
+		if v != "" {
+			t.Fatalf("results[%d] = %v, want empty string (zero value from closed channel)", i, v)
+		}
+	}
+}
+
+// TestGroupDoSeparateKeys 验证不同的 key 各自独立调用 fn
+func TestGroupDoSeparateKeys(t *testing.T) {
+	var g Group
+	v1, err := g.Do("k1", func() (interface{}, error) { return 1, nil })
+	if err != nil || v1 != 1 {
+		t.Fatalf("Do(k1) = %v, %v, want 1, nil", v1, err)
+	}
+	v2, err := g.Do("k2", func() (interface{}, error) { return 2, nil })
+	if err != nil || v2 != 2 {
+		t.Fatalf("Do(k2) = %v, %v, want 2, nil", v2, err)
+	}
+}
+
+// TestGroupDoError 验证 fn 返回的 error 会原样返回给调用方，且调用结束后 key 被清理
+func TestGroupDoError(t *testing.T) {
+	var g Group
+	wantErr := errors.New("boom")
+	v, err := g.Do("key", func() (interface{}, error) {
+		return nil, wantErr
+	})
+	if err != wantErr || v != nil {
+		t.Fatalf("Do() = %v, %v, want nil, %v", v, err, wantErr)
+	}
+
+	// key 已经被清理，后续调用应当重新执行 fn
+	var calls int32
+	_, _ = g.Do("key", func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, nil
+	})
+	if calls != 1 {
+		t.Fatalf("fn called %d times after previous call finished, want 1", calls)
+	}
+}
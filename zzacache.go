@@ -3,9 +3,17 @@ package zzacache
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"sync"
+	"time"
+	"zzacache/lru"
+	"zzacache/singleflight"
+	pb "zzacache/zzacachepb"
 )
 
+// hotCache 分得的字节预算占总预算的比例为 1/hotCacheDivisor，其余归 mainCache 所有
+const hotCacheDivisor = 8
+
 // Getter 接口定义了方法签名 Get(key string) ([]byte, error)
 // 任何类型只要实现了该方法，就自动实现了 Getter 接口
 type Getter interface {
@@ -20,6 +28,15 @@ func (f GetterFunc) Get(key string) ([]byte, error) {
 	return f(key)
 }
 
+// GetterWithTTL 是 Getter 的一个独立的变体：数据源在返回数据的同时，还可以为该 key
+// 声明一个存活时间，从而让不同的 key 拥有不同的过期时间。
+// 注意它与 Getter 是互斥的，不是可选扩展——Go 不支持方法重载，没有任何具体类型能够
+// 同时实现签名不同的 Getter.Get 和 GetterWithTTL.Get，因此一个 Group 只能持有其中一个，
+// 通过 NewGroupWithTTLGetter 而不是 NewGroup/NewGroupWithPolicy 来构造
+type GetterWithTTL interface {
+	Get(key string) ([]byte, time.Duration, error)
+}
+
 /*
 一个 Group 可以认为是一个缓存的命名空间
 每个 Group 拥有一个唯一的名称 name
@@ -31,11 +48,18 @@ func (f GetterFunc) Get(key string) ([]byte, error) {
 type Group struct {
 	// 分组名称，作为缓存的命名空间
 	name string
-	// 缓存未命中时获取源数据的回调
+	// 缓存未命中时获取源数据的回调；与 ttlGetter 互斥，只有其中一个会被设置，
+	// 具体取决于 Group 是通过 NewGroup/NewGroupWithPolicy 还是 NewGroupWithTTLGetter 创建的
 	getter Getter
-	// 缓存实例（内部类型 cache），存储实际的缓存数据
+	// ttlGetter 是 getter 的 TTL 变体，参见 GetterWithTTL 的说明
+	ttlGetter GetterWithTTL
+	// 缓存实例（内部类型 cache），存储本节点拥有的缓存数据
 	mainCache cache
-	peers     PeerPicker
+	// hotCache 存储从其他节点获取的热点数据的副本，用于分摊跨节点的重复请求
+	hotCache cache
+	peers    PeerPicker
+	// loader 保证每个 key 在同一时刻只会被加载一次，无论并发的调用方有多少
+	loader *singleflight.Group
 }
 
 // 通过 sync.RWMutex 实现并发安全的全局注册表，存储所有已创建的 Group 实例
@@ -44,21 +68,72 @@ var (
 	groups = make(map[string]*Group)
 )
 
+// NewGroup 创建一个使用默认 LRU 淘汰策略的 Group，与旧版本保持兼容
 func NewGroup(name string, cacheBytes int64, getter Getter) *Group {
+	return NewGroupWithPolicy(name, cacheBytes, nil, getter)
+}
+
+// NewGroupWithPolicy 与 NewGroup 类似，但允许为 mainCache 指定淘汰策略，例如访问分布明显
+// 偏斜的场景可以选用 lru.NewLFUPolicy，类队列场景可以选用 lru.NewFIFOPolicy；newPolicy 为 nil
+// 时回退到默认的 LRU。hotCache 用于短暂存放跨节点的热点副本，始终使用默认策略
+func NewGroupWithPolicy(name string, cacheBytes int64, newPolicy lru.PolicyConstructor, getter Getter) *Group {
 	if getter == nil {
 		panic("nil Getter")
 	}
 	mu.Lock()
 	defer mu.Unlock()
-	g := &Group{
-		name:      name,
-		getter:    getter,
-		mainCache: cache{cacheBytes: cacheBytes},
+	g := newGroup(name, cacheBytes, newPolicy)
+	g.getter = getter
+	groups[name] = g
+	return g
+}
+
+// NewGroupWithTTLGetter 与 NewGroupWithPolicy 类似，但数据源通过 GetterWithTTL 为每个 key
+// 声明各自的存活时间。由于 GetterWithTTL 不能与 Getter 被同一个具体类型同时实现，
+// 需要这个专门的构造函数，而不是给 NewGroup/NewGroupWithPolicy 做类型断言
+func NewGroupWithTTLGetter(name string, cacheBytes int64, newPolicy lru.PolicyConstructor, getter GetterWithTTL) *Group {
+	if getter == nil {
+		panic("nil GetterWithTTL")
 	}
+	mu.Lock()
+	defer mu.Unlock()
+	g := newGroup(name, cacheBytes, newPolicy)
+	g.ttlGetter = getter
 	groups[name] = g
 	return g
 }
 
+// newGroup 构造一个尚未设置 getter/ttlGetter 的 Group，供上述构造函数共用
+func newGroup(name string, cacheBytes int64, newPolicy lru.PolicyConstructor) *Group {
+	mainBytes, hotBytes := splitCacheBytes(cacheBytes)
+	return &Group{
+		name:      name,
+		mainCache: cache{cacheBytes: mainBytes, newPolicy: newPolicy},
+		hotCache:  cache{cacheBytes: hotBytes},
+		loader:    &singleflight.Group{},
+	}
+}
+
+// splitCacheBytes 按 hotCacheDivisor 把 cacheBytes 分给 mainCache 和 hotCache。
+// lru.Cache 把 maxBytes == 0 当作“不限制”，而 cacheBytes/hotCacheDivisor 在
+// cacheBytes < hotCacheDivisor 时会整除成 0，导致 hotCache 悄悄退化成无界缓存，
+// 侵占本应只属于 mainCache 的预算。因此 cacheBytes > 0 时至少给 hotCache 留 1 字节，
+// 使它始终是一个真正受限的缓存
+func splitCacheBytes(cacheBytes int64) (mainBytes, hotBytes int64) {
+	if cacheBytes <= 0 {
+		return cacheBytes, cacheBytes
+	}
+	hotBytes = cacheBytes / hotCacheDivisor
+	if hotBytes == 0 {
+		hotBytes = 1
+	}
+	mainBytes = cacheBytes - hotBytes
+	if mainBytes == 0 {
+		mainBytes = 1
+	}
+	return
+}
+
 // GetGroup 函数返回之前通过 NewGroup 创建的、具有指定名称的 group
 // 如果不存在该名称的 group，则返回 nil
 func GetGroup(name string) *Group {
@@ -71,35 +146,74 @@ func GetGroup(name string) *Group {
 
 // Get value for a key from cache
 func (g *Group) Get(key string) (ByteView, error) {
+	v, _, _, err := g.getWithExpireAt(key)
+	return v, err
+}
+
+// getWithExpireAt 是 Get 的实现，额外返回该 key 的过期时间（零值表示永不过期），
+// 以及该值是否命中于 hotCache（而非本节点拥有的 mainCache）。供 HTTPPool.ServerHTTP
+// 使用：值和过期时间在命中 mainCache/hotCache 时于同一次加锁内一并读出，避免分两次
+// 独立查询之间该 key 被淘汰或被覆盖写入新的 TTL，导致响应带着一个与实际返回的值不
+// 对应的过期时间（典型表现是错误地回传"永不过期"）；fromHotCache 则用于告知对端
+// 这份数据本身已经是热点副本，对端不必再把它当作热点重新复制一遍
+func (g *Group) getWithExpireAt(key string) (value ByteView, expireAt time.Time, fromHotCache bool, err error) {
 	if key == "" {
-		return ByteView{}, fmt.Errorf("key不能为空")
+		return ByteView{}, time.Time{}, false, fmt.Errorf("key不能为空")
 	}
 
 	// 从 mainCache 中查找缓存，如果存在则返回缓存值
-	if v, ok := g.mainCache.get(key); ok {
+	if v, t, ok := g.mainCache.getWithExpireAt(key); ok {
 		log.Println("[ZzaCache] hit")
-		return v, nil
+		return v, t, false, nil
+	}
+	// mainCache 未命中，再查找 hotCache 中是否有其他节点同步过来的热点副本
+	if v, t, ok := g.hotCache.getWithExpireAt(key); ok {
+		log.Println("[ZzaCache] hit (hotCache)")
+		return v, t, true, nil
 	}
 
-	// 缓存不存在，则调用 load 方法
-	return g.load(key)
+	// 缓存不存在，则调用 load 方法；load 的结果要么来自本节点的数据源，要么来自其他
+	// 拥有该 key 的节点，对本节点而言都不是"hotCache 副本"
+	v, err := g.load(key)
+	if err != nil {
+		return ByteView{}, time.Time{}, false, err
+	}
+	return v, g.expireAt(key), false, nil
 }
 
 // load 使用 PickPeer() 方法选择节点，若非本机节点，则调用 getFromPeer() 从远程获取。若是本机节点或失败，则回退到 getLocally()
+// 借助 g.loader，确保并发的请求中，相同的 key 无论被 Get() 多少次，load 只会被执行一次
 func (g *Group) load(key string) (value ByteView, err error) {
-	if g.peers != nil {
-		if peer, ok := g.peers.PickPeer(key); ok {
-			if value, err = g.getFromPeer(peer, key); err == nil {
-				return value, nil
+	viewi, err := g.loader.Do(key, func() (interface{}, error) {
+		if g.peers != nil {
+			if peer, ok := g.peers.PickPeer(key); ok {
+				if value, err = g.getFromPeer(peer, key); err == nil {
+					return value, nil
+				}
+				log.Println("[GeeCache] Failed to get from peer", err)
 			}
-			log.Println("[GeeCache] Failed to get from peer", err)
 		}
+		return g.getLocally(key)
+	})
+	if err == nil {
+		return viewi.(ByteView), nil
 	}
-	return g.getLocally(key)
+	return
 }
 
-// getLocally 调用用户回调函数 g.getter.Get() 获取源数据
+// getLocally 调用数据源获取源数据；Group 要么持有 getter，要么持有 ttlGetter（互斥，
+// 取决于创建时调用的是哪个构造函数），后者额外携带了该 key 的存活时间并写入 mainCache
 func (g *Group) getLocally(key string) (ByteView, error) {
+	if g.ttlGetter != nil {
+		bytes, ttl, err := g.ttlGetter.Get(key)
+		if err != nil {
+			return ByteView{}, err
+		}
+		value := ByteView{b: cloneBytes(bytes)}
+		g.mainCache.addWithTTL(key, value, ttl)
+		return value, nil
+	}
+
 	bytes, err := g.getter.Get(key)
 	if err != nil {
 		return ByteView{}, err
@@ -123,10 +237,64 @@ func (g *Group) RegisterPeers(peers PeerPicker) {
 }
 
 // getFromPeer 使用实现了 PeerGetter 接口的 httpGetter 从访问远程节点，获取缓存
+// 约有 1/10 的命中会被额外写入 hotCache，使得后续同一 key 的请求能直接在本地命中，
+// 不必每次都转发给真正拥有该 key 的节点。对端通过 Response.ExpireAt 携带该 key
+// 剩余的存活时间，hotCache 中的副本据此过期，避免热点副本比源数据活得更久。
+// 若对端返回的数据本身就是它的 hotCache 副本（Response.FromHotcache），则不再缓存一份，
+// 避免出现"hotCache 缓存 hotCache"的链式复制
 func (g *Group) getFromPeer(peer PeerGetter, key string) (ByteView, error) {
-	bytes, err := peer.Get(g.name, key)
+	req := &pb.Request{
+		Group: g.name,
+		Key:   key,
+	}
+	res := &pb.Response{}
+	err := peer.Get(req, res)
 	if err != nil {
 		return ByteView{}, err
 	}
-	return ByteView{b: bytes}, nil
+	value := ByteView{b: res.Value}
+	if !res.FromHotcache && rand.Intn(10) == 0 {
+		var ttl time.Duration
+		if res.ExpireAt > 0 {
+			if ttl = time.Until(time.Unix(res.ExpireAt, 0)); ttl <= 0 {
+				return value, nil // 对端的数据已经过期，不必再缓存一份
+			}
+		}
+		g.hotCache.addWithTTL(key, value, ttl)
+	}
+	return value, nil
+}
+
+// expireAt 返回 key 在 mainCache 或 hotCache 中的过期时间，零值表示永不过期或未找到；
+// 供 HTTPPool.ServerHTTP 在响应中回传剩余 TTL，使热点副本在集群内保持一致的过期时间
+func (g *Group) expireAt(key string) time.Time {
+	if t, ok := g.mainCache.expireAt(key); ok {
+		return t
+	}
+	if t, ok := g.hotCache.expireAt(key); ok {
+		return t
+	}
+	return time.Time{}
+}
+
+// StartJanitor 启动后台 goroutine，按 interval 周期性扫描并清理 mainCache 与 hotCache
+// 中已过期的条目，在没有读写触发惰性淘汰的情况下也能及时释放内存
+func (g *Group) StartJanitor(interval time.Duration) {
+	g.mainCache.startJanitor(interval)
+	g.hotCache.startJanitor(interval)
+}
+
+// CacheStats 记录某一级缓存的命中情况
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// Stats 返回 mainCache 和 hotCache 各自的命中/未命中统计，便于观测两级缓存的效果
+func (g *Group) Stats() (main, hot CacheStats) {
+	mainHits, mainGets := g.mainCache.stats()
+	hotHits, hotGets := g.hotCache.stats()
+	main = CacheStats{Hits: mainHits, Misses: mainGets - mainHits}
+	hot = CacheStats{Hits: hotHits, Misses: hotGets - hotHits}
+	return
 }
@@ -0,0 +1,7 @@
+package zzacachepb
+
+import "errors"
+
+// ErrNotFound 由对端在响应中携带，表示请求的 key 在该节点上不存在，
+// 与连接失败、服务端内部错误等传输层错误区分开
+var ErrNotFound = errors.New("zzacachepb: key not found")
@@ -0,0 +1,42 @@
+package zzacachepb
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// TestRequestRoundTrip 验证 Request 经 Marshal/Unmarshal 后字段保持不变
+func TestRequestRoundTrip(t *testing.T) {
+	in := &Request{Group: "scores", Key: "Tom"}
+	data, err := proto.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	out := &Request{}
+	if err := proto.Unmarshal(data, out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out.GetGroup() != in.GetGroup() || out.GetKey() != in.GetKey() {
+		t.Fatalf("round-trip mismatch: got %+v, want %+v", out, in)
+	}
+}
+
+// TestResponseRoundTrip 验证 Response 经 Marshal/Unmarshal 后字段保持不变，
+// 包括 ExpireAt/FromHotcache 这两个后续追加的字段
+func TestResponseRoundTrip(t *testing.T) {
+	in := &Response{Value: []byte("630"), ExpireAt: 1690000000, FromHotcache: true}
+	data, err := proto.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	out := &Response{}
+	if err := proto.Unmarshal(data, out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if string(out.GetValue()) != string(in.GetValue()) ||
+		out.GetExpireAt() != in.GetExpireAt() ||
+		out.GetFromHotcache() != in.GetFromHotcache() {
+		t.Fatalf("round-trip mismatch: got %+v, want %+v", out, in)
+	}
+}